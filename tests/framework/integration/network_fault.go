@@ -0,0 +1,185 @@
+// Copyright 2016 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// FaultProfile describes a set of network impairments to apply to a
+// connection, as an alternative to the all-or-nothing blackhole used by
+// InjectPartition/InjectPartitionOneWay.
+type FaultProfile struct {
+	// Latency is the fixed one-way delay applied to every write.
+	Latency time.Duration
+	// Jitter is the mean of an additional delay layered on top of Latency,
+	// drawn from an exponential distribution; the draw is not capped.
+	Jitter time.Duration
+	// LossRate is the probability, in [0,1], that a write suffers a
+	// retransmission-timeout-like stall. Real IP-level loss below a
+	// reliable stream is invisible to the application, so this delays
+	// the write rather than dropping its bytes, which would desync the
+	// stream's framing.
+	LossRate float64
+	// DuplicateRate is the probability, in [0,1], that a write is
+	// delivered twice.
+	DuplicateRate float64
+	// ReorderRate is the probability, in [0,1], that a write is held
+	// back and flushed after the write that follows it.
+	ReorderRate float64
+	// BandwidthBps caps sustained throughput with a token bucket. Zero
+	// means unlimited.
+	BandwidthBps int64
+}
+
+// InjectFault dials m through a connection degraded according to profile
+// and returns it as a ready-to-use client. Unlike InjectPartition and
+// InjectPartitionOneWay, which pause an already-established raft peer
+// link, a fault profile can only be applied as a connection is dialed, so
+// it has no effect on connections the cluster already opened; callers get
+// a brand-new client instead and should exercise the returned client,
+// closing it once done.
+func (m *Member) InjectFault(t testutil.TB, profile FaultProfile) *clientv3.Client {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{m.GRPCURL},
+		DialOptions: []grpc.DialOption{FaultDialOption(profile)},
+	})
+	if err != nil {
+		t.Fatalf("failed to dial %s with fault profile: %v", m.GRPCURL, err)
+	}
+	return cli
+}
+
+// FaultDialOption returns a grpc.DialOption that establishes connections
+// the same way grpc normally would, then wraps them so profile's
+// impairments apply to every Write. Reads are passed through unchanged.
+func FaultDialOption(profile FaultProfile) grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		return newFaultConn(conn, profile), nil
+	})
+}
+
+// faultConn wraps a net.Conn, applying latency/jitter, a token-bucket
+// bandwidth cap, and Bernoulli-draw loss/duplication/reordering to every
+// Write.
+type faultConn struct {
+	net.Conn
+	profile FaultProfile
+
+	bucket     float64
+	lastRefill time.Time
+
+	held []byte // single-slot reorder buffer
+}
+
+func newFaultConn(conn net.Conn, profile FaultProfile) *faultConn {
+	return &faultConn{
+		Conn:       conn,
+		profile:    profile,
+		bucket:     float64(profile.BandwidthBps),
+		lastRefill: time.Now(),
+	}
+}
+
+func (c *faultConn) Write(b []byte) (int, error) {
+	if c.profile.LossRate > 0 && rand.Float64() < c.profile.LossRate {
+		time.Sleep(c.retransmitDelay())
+	}
+
+	c.throttle(len(b))
+	time.Sleep(c.delay())
+
+	if c.profile.ReorderRate > 0 && len(c.held) == 0 && rand.Float64() < c.profile.ReorderRate {
+		// Hold this write back instead of sending it now: it is flushed
+		// right after the next write instead, so the wire sees the two
+		// writes in swapped order and the underlying bytes are written
+		// exactly once.
+		c.held = append([]byte(nil), b...)
+		return len(b), nil
+	}
+
+	out := b
+	if len(c.held) > 0 {
+		held := c.held
+		c.held = nil
+		out = append(append([]byte(nil), b...), held...)
+	}
+
+	if _, err := c.Conn.Write(out); err != nil {
+		return 0, err
+	}
+
+	if c.profile.DuplicateRate > 0 && rand.Float64() < c.profile.DuplicateRate {
+		if _, err := c.Conn.Write(b); err != nil {
+			return len(b), err
+		}
+	}
+
+	return len(b), nil
+}
+
+// delay draws the per-write latency: a fixed base plus an
+// exponentially-distributed jitter component.
+func (c *faultConn) delay() time.Duration {
+	d := c.profile.Latency
+	if c.profile.Jitter > 0 {
+		d += time.Duration(rand.ExpFloat64() * float64(c.profile.Jitter))
+	}
+	return d
+}
+
+// retransmitDelay approximates the extra time a real retransmission would
+// add once a segment is lost underneath a reliable stream.
+func (c *faultConn) retransmitDelay() time.Duration {
+	base := c.profile.Latency + c.profile.Jitter
+	if base <= 0 {
+		base = 200 * time.Millisecond
+	}
+	return 3 * base
+}
+
+// throttle blocks until the token bucket has accumulated enough capacity
+// for n bytes, capping sustained throughput at profile.BandwidthBps.
+func (c *faultConn) throttle(n int) {
+	if c.profile.BandwidthBps <= 0 {
+		return
+	}
+	now := time.Now()
+	c.bucket += now.Sub(c.lastRefill).Seconds() * float64(c.profile.BandwidthBps)
+	if max := float64(c.profile.BandwidthBps); c.bucket > max {
+		c.bucket = max
+	}
+	c.lastRefill = now
+
+	if deficit := float64(n) - c.bucket; deficit > 0 {
+		time.Sleep(time.Duration(deficit / float64(c.profile.BandwidthBps) * float64(time.Second)))
+		c.bucket = 0
+		return
+	}
+	c.bucket -= float64(n)
+}
@@ -0,0 +1,35 @@
+// Copyright 2016 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import "go.etcd.io/etcd/client/pkg/v3/testutil"
+
+// InjectPartitionOneWay drops the raft peer connection from m to each of
+// others, while leaving the reverse direction (others -> m) intact. Unlike
+// InjectPartition, which blackholes both directions, this reproduces
+// one-way network failures such as asymmetric firewall/ACL rules or a NIC
+// that can transmit but not receive.
+func (m *Member) InjectPartitionOneWay(t testutil.TB, others ...*Member) {
+	for _, other := range others {
+		m.Server.CutPeer(other.Server.MemberID())
+	}
+}
+
+// RecoverPartitionOneWay reverses InjectPartitionOneWay.
+func (m *Member) RecoverPartitionOneWay(t testutil.TB, others ...*Member) {
+	for _, other := range others {
+		m.Server.MendPeer(other.Server.MemberID())
+	}
+}
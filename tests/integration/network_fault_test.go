@@ -0,0 +1,98 @@
+// Copyright 2016 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestNetworkFaultLatencyAndLossClusterProgresses verifies that a 5-node
+// cluster keeps serving writes to every member when clients dial in over
+// degraded, WAN-like links (latency, jitter, and packet loss), rather than
+// the clean all-or-nothing blackholes exercised by the partition tests
+// in network_partition_test.go.
+func TestNetworkFaultLatencyAndLossClusterProgresses(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 5})
+	defer clus.Terminate(t)
+
+	clus.WaitLeader(t)
+
+	profile := integration.FaultProfile{
+		Latency:  200 * time.Millisecond,
+		Jitter:   50 * time.Millisecond,
+		LossRate: 0.05,
+	}
+
+	// every member must still accept writes from a client dialed through
+	// a degraded link.
+	for i, m := range clus.Members {
+		cli := m.InjectFault(t, profile)
+		_, err := cli.Put(context.Background(), fmt.Sprintf("fault-key-%d", i), "v1")
+		require.NoError(t, err)
+		require.NoError(t, cli.Close())
+	}
+
+	clus.WaitLeader(t)
+	clusterMustProgress(t, clus.Members)
+}
+
+// TestNetworkFaultDegradedLeaderLinkLinearizableRead verifies that a write
+// accepted through a degraded connection to the leader is still visible,
+// at a revision no older than the write itself, to a linearizable read
+// against a different, undegraded follower connection — i.e. the cluster
+// cannot be tricked into serving a stale read just because the path to
+// the leader is slow and lossy. Only the client's writes to the leader are
+// degraded; the leader's raft links to its peers are left as they are.
+func TestNetworkFaultDegradedLeaderLinkLinearizableRead(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 5})
+	defer clus.Terminate(t)
+
+	leadIndex := clus.WaitLeader(t)
+	lead := clus.Members[leadIndex]
+	followerIndex := (leadIndex + 1) % 5
+
+	profile := integration.FaultProfile{
+		Latency:  200 * time.Millisecond,
+		Jitter:   50 * time.Millisecond,
+		LossRate: 0.05,
+	}
+	leadCli := lead.InjectFault(t, profile)
+	defer leadCli.Close()
+
+	putResp, err := leadCli.Put(context.Background(), "fault-key", "v1")
+	require.NoError(t, err)
+
+	// read from a different member over a normal connection: it must
+	// observe the write that just went through the degraded leader link,
+	// at a revision that is not stale.
+	followerCli := clus.Client(followerIndex)
+	getResp, err := followerCli.Get(context.Background(), "fault-key")
+	require.NoError(t, err)
+	require.Len(t, getResp.Kvs, 1)
+	require.Equal(t, "v1", string(getResp.Kvs[0].Value))
+	require.GreaterOrEqualf(t, getResp.Header.Revision, putResp.Header.Revision,
+		"linearizable read from follower returned stale revision %d, want >= %d", getResp.Header.Revision, putResp.Header.Revision)
+}
@@ -139,6 +139,80 @@ func TestNetworkPartition4Members(t *testing.T) {
 	clusterMustProgress(t, clus.Members)
 }
 
+// TestAsymmetricPartitionLeaderSendsButCannotReceive verifies that a leader
+// which can still send traffic out but can no longer hear from its peers
+// (e.g. a one-way firewall/ACL misconfiguration) steps down once its
+// election timeout elapses, rather than wedging the cluster with a leader
+// that believes it still has quorum.
+func TestAsymmetricPartitionLeaderSendsButCannotReceive(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 5})
+	defer clus.Terminate(t)
+
+	leadIndex := clus.WaitLeader(t)
+	lead := clus.Members[leadIndex]
+	others := getMembersByIndexSlice(clus, othersOf(leadIndex, 5))
+
+	// the leader can still send to others, but inbound traffic (votes,
+	// AppendEntries acks) from others is dropped: cut others -> lead,
+	// leaving lead -> others intact.
+	injectPartitionOneWay(t, others, []*integration.Member{lead})
+
+	// the leader must lose quorum and step down.
+	clus.WaitMembersNoLeader([]*integration.Member{lead})
+
+	// wait extra election timeout
+	time.Sleep(2 * lead.ElectionTimeout())
+
+	// a new leader must be elected among the members that can still hear
+	// each other.
+	clus.WaitMembersForLeader(t, others)
+
+	recoverPartitionOneWay(t, others, []*integration.Member{lead})
+
+	clusterMustProgress(t, append(others, lead))
+}
+
+// TestAsymmetricPartitionFollowerCannotReachLeader verifies that a minority
+// follower whose outbound traffic is dropped, but which can still receive
+// AppendEntries from the leader, does not stall progress for the rest of
+// the cluster.
+func TestAsymmetricPartitionFollowerCannotReachLeader(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 5})
+	defer clus.Terminate(t)
+
+	leadIndex := clus.WaitLeader(t)
+	followerIndex := (leadIndex + 1) % 5
+	follower := clus.Members[followerIndex]
+	others := getMembersByIndexSlice(clus, othersOf(followerIndex, 5))
+
+	// the follower can still receive from others, but nothing it sends
+	// (vote requests, AppendEntries acks) reaches them.
+	injectPartitionOneWay(t, []*integration.Member{follower}, others)
+
+	// the leader must remain unaffected and the cluster must keep making
+	// progress despite the wedged follower.
+	clus.WaitLeader(t)
+	clusterMustProgress(t, others)
+
+	recoverPartitionOneWay(t, []*integration.Member{follower}, others)
+
+	clusterMustProgress(t, append(others, follower))
+}
+
+func othersOf(idx, size int) []int {
+	others := make([]int, 0, size-1)
+	for i := 0; i < size; i++ {
+		if i != idx {
+			others = append(others, i)
+		}
+	}
+	return others
+}
+
 func getMembersByIndexSlice(clus *integration.Cluster, idxs []int) []*integration.Member {
 	ms := make([]*integration.Member, len(idxs))
 	for i, idx := range idxs {
@@ -158,3 +232,18 @@ func recoverPartition(t *testing.T, src, others []*integration.Member) {
 		m.RecoverPartition(t, others...)
 	}
 }
+
+// injectPartitionOneWay drops traffic from src to others, while leaving the
+// reverse direction (others -> src) intact.
+func injectPartitionOneWay(t *testing.T, src, others []*integration.Member) {
+	for _, m := range src {
+		m.InjectPartitionOneWay(t, others...)
+	}
+}
+
+// recoverPartitionOneWay reverses injectPartitionOneWay.
+func recoverPartitionOneWay(t *testing.T, src, others []*integration.Member) {
+	for _, m := range src {
+		m.RecoverPartitionOneWay(t, others...)
+	}
+}